@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "regexp"
+
+// TokenFlavor enumerates the different shapes a C++ expression or value literal can take once it
+// shows up in a parsed header. The JS, TS, and Java emitters each need to know the flavor of a
+// token before they can decide how to transmogrify it, so this lives in one place instead of being
+// re-derived by ad hoc string prefix checks in every template extension.
+type TokenFlavor int
+
+const (
+	// FlavorUnknown is returned when no candidate matches with any confidence; callers should fall
+	// back to their pre-existing heuristics rather than failing outright.
+	FlavorUnknown TokenFlavor = iota
+	FlavorNumericLiteral
+	FlavorMathVector
+	FlavorEnumValue
+	FlavorPreprocessorToken
+	FlavorScopedSymbol
+)
+
+// flavorCandidate describes one pattern beamsplitter knows how to recognize, along with the
+// confidence it should report on a match. Candidates are tried in order and the highest scoring
+// match wins; a tie falls back to the first candidate registered, which preserves the behavior of
+// the original hand-rolled checks in tstype/qualifiedvalue.
+type flavorCandidate struct {
+	flavor     TokenFlavor
+	pattern    *regexp.Regexp
+	prefix     string
+	confidence float64
+}
+
+// classifier scores a token against a small set of pattern-based candidates and an optional table
+// of known symbols (e.g. enum value names harvested from the parsed definitions). It is shared by
+// createJsCodeGenerator and createJavaCodeGenerator so that JS, TS, and Java emit consistent
+// results for the same input token.
+type classifier struct {
+	candidates  []flavorCandidate
+	knownSymbol map[string]TokenFlavor
+}
+
+// newClassifier builds the default candidate list. knownSymbols maps a fully qualified name (e.g.
+// "View::BlendMode::OPAQUE") to the flavor it should always resolve to; this is populated from the
+// parsed TypeDefinition list so that classification stays correct even when a token's spelling
+// alone is ambiguous.
+func newClassifier(knownSymbols map[string]TokenFlavor) *classifier {
+	return &classifier{
+		candidates: []flavorCandidate{
+			// Matches both a constructor call (math::float3(1, 0, 0)) and the bare type spelling
+			// (math::float3) so tstype/java_type can classify a field's declared type, not just a
+			// value literal.
+			{FlavorMathVector, regexp.MustCompile(`^math::\w+(\(.*\))?$`), "math::", 0.95},
+			{FlavorEnumValue, regexp.MustCompile(`^\w+(::\w+)+$`), "", 0.7},
+			{FlavorPreprocessorToken, regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`), "", 0.6},
+			{FlavorNumericLiteral, regexp.MustCompile(`^-?\d+(\.\d+)?[fF]?$`), "", 0.9},
+			{FlavorScopedSymbol, regexp.MustCompile(`::`), "", 0.3},
+		},
+		knownSymbol: knownSymbols,
+	}
+}
+
+// classify returns the best-matching flavor for token along with the confidence of that match.
+// Known symbols always win outright (confidence 1.0) since they come directly from the parsed
+// definitions rather than a guess. Ties between pattern candidates resolve in registration order,
+// which mirrors the precedence the original string-prefix checks used.
+func (c *classifier) classify(token string) (TokenFlavor, float64) {
+	if flavor, ok := c.knownSymbol[token]; ok {
+		return flavor, 1.0
+	}
+
+	best := FlavorUnknown
+	bestScore := 0.0
+	for _, candidate := range c.candidates {
+		if candidate.prefix != "" && !hasPrefix(token, candidate.prefix) {
+			continue
+		}
+		if candidate.pattern != nil && !candidate.pattern.MatchString(token) {
+			continue
+		}
+		if candidate.confidence > bestScore {
+			best = candidate.flavor
+			bestScore = candidate.confidence
+		}
+	}
+	return best, bestScore
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+// buildKnownSymbols populates the known-symbol table newClassifier takes from a parsed definition
+// list: every struct and enum name resolves to FlavorScopedSymbol, and every enumerator (qualified
+// as "EnumName::VALUE") resolves to FlavorEnumValue, so that classification of those names doesn't
+// depend on pattern guessing at all.
+func buildKnownSymbols(definitions []TypeDefinition) map[string]TokenFlavor {
+	known := make(map[string]TokenFlavor, len(definitions))
+	for _, d := range definitions {
+		switch t := d.(type) {
+		case *StructDefinition:
+			known[t.Name] = FlavorScopedSymbol
+		case *EnumDefinition:
+			known[t.Name] = FlavorScopedSymbol
+			for _, value := range t.Values {
+				known[t.Name+"::"+value] = FlavorEnumValue
+			}
+		}
+	}
+	return known
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "testing"
+
+func TestClassifierClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		known  map[string]TokenFlavor
+		token  string
+		flavor TokenFlavor
+	}{
+		{"math vector bare type", nil, "math::float3", FlavorMathVector},
+		{"math vector constructor call", nil, "math::float3(1, 0, 0)", FlavorMathVector},
+		{"enum qualified value", nil, "View::BlendMode::OPAQUE", FlavorEnumValue},
+		{"preprocessor token", nil, "FILAMENT_API", FlavorPreprocessorToken},
+		{"numeric literal", nil, "1.5f", FlavorNumericLiteral},
+		{"unscoped identifier", nil, "count", FlavorUnknown},
+		{"known symbol beats pattern guessing", map[string]TokenFlavor{"View::BlendMode": FlavorScopedSymbol}, "View::BlendMode", FlavorScopedSymbol},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newClassifier(test.known)
+			flavor, confidence := c.classify(test.token)
+			if flavor != test.flavor {
+				t.Errorf("classify(%q) = %v, want %v", test.token, flavor, test.flavor)
+			}
+			if test.flavor != FlavorUnknown && confidence <= 0 {
+				t.Errorf("classify(%q) returned non-positive confidence %v for a matched flavor", test.token, confidence)
+			}
+		})
+	}
+}
+
+func TestClassifierKnownSymbolOutranksPatterns(t *testing.T) {
+	// "FOO::BAR" would otherwise classify as FlavorEnumValue via the pattern table; a known-symbol
+	// override should win with full confidence regardless.
+	c := newClassifier(map[string]TokenFlavor{"FOO::BAR": FlavorScopedSymbol})
+	flavor, confidence := c.classify("FOO::BAR")
+	if flavor != FlavorScopedSymbol {
+		t.Errorf("classify(%q) = %v, want FlavorScopedSymbol", "FOO::BAR", flavor)
+	}
+	if confidence != 1.0 {
+		t.Errorf("classify(%q) confidence = %v, want 1.0", "FOO::BAR", confidence)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateVersion and extensionFuncsVersion are bumped by hand whenever javascript.template,
+// java.template, or the template.FuncMap implementations change in a way that affects emitted
+// output, so that a stale .beamsplitter-cache gets invalidated even if no TypeDefinition changed.
+const (
+	templateVersion       = "1"
+	extensionFuncsVersion = "1"
+)
+
+// dependencyGraph maps a definition's fully qualified name to the names of the definitions it
+// depends on: a struct depends on the types of its fields, and (transitively) on any enum those
+// field types reference.
+type dependencyGraph map[string][]string
+
+// cycleError reports a circular dependency found while traversing the graph, together with the
+// path that led back to the starting node, similar to how a dependency resolver reports cycles.
+type cycleError struct {
+	path []string
+}
+
+func (e *cycleError) Error() string {
+	return "circular dependency detected: " + strings.Join(e.path, " -> ")
+}
+
+// BuildDependencyGraph walks definitions and records, for each struct, the names of the other
+// definitions it references via its fields. Enums have no dependencies of their own. The resulting
+// graph is validated for cycles before being returned.
+func BuildDependencyGraph(definitions []TypeDefinition) (dependencyGraph, error) {
+	byName := make(map[string]TypeDefinition, len(definitions))
+	for _, d := range definitions {
+		byName[definitionName(d)] = d
+	}
+
+	graph := make(dependencyGraph, len(definitions))
+	for _, d := range definitions {
+		name := definitionName(d)
+		s, ok := d.(*StructDefinition)
+		if !ok {
+			graph[name] = nil
+			continue
+		}
+		var deps []string
+		for _, field := range s.Fields {
+			// math:: vector/matrix fields aren't themselves TypeDefinitions, so stripping the
+			// namespace here only ever matches when the bare name also happens to be a struct or
+			// enum beamsplitter parsed (e.g. a field typed as another generated struct).
+			fieldType := strings.TrimPrefix(field.CppType, "math::")
+			if _, known := byName[fieldType]; known {
+				deps = append(deps, fieldType)
+			}
+		}
+		graph[name] = deps
+	}
+
+	if path := findCycle(graph); path != nil {
+		return nil, &cycleError{path: path}
+	}
+	return graph, nil
+}
+
+// findCycle returns the node path of the first circular dependency it finds via depth-first
+// traversal, or nil if the graph is acyclic.
+func findCycle(graph dependencyGraph) []string {
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(graph))
+	var path []string
+
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		state[node] = visiting
+		path = append(path, node)
+		for _, dep := range graph[node] {
+			switch state[dep] {
+			case visiting:
+				return append(append([]string{}, path...), dep)
+			case unvisited:
+				if cyclePath := visit(dep); cyclePath != nil {
+					return cyclePath
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[node] = done
+		return nil
+	}
+
+	for node := range graph {
+		if state[node] == unvisited {
+			if cyclePath := visit(node); cyclePath != nil {
+				return cyclePath
+			}
+		}
+	}
+	return nil
+}
+
+// transitiveDependents returns, for each changed definition name, every other definition that
+// (transitively) depends on it — those must be regenerated too even though their own content
+// didn't change, since the generated code embeds the types of their fields.
+func transitiveDependents(graph dependencyGraph, changed map[string]bool) map[string]bool {
+	dependents := make(map[string][]string, len(graph))
+	for name, deps := range graph {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	result := make(map[string]bool, len(changed))
+	var mark func(name string)
+	mark = func(name string) {
+		if result[name] {
+			return
+		}
+		result[name] = true
+		for _, dependent := range dependents[name] {
+			mark(dependent)
+		}
+	}
+	for name := range changed {
+		mark(name)
+	}
+	return result
+}
+
+// hashDefinition computes a stable content hash of a single definition, salted with the template
+// and extension-funcs versions so that a change to the templates invalidates every cache entry
+// without needing to touch TypeDefinition itself.
+func hashDefinition(d TypeDefinition) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%#v", templateVersion, extensionFuncsVersion, d)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheFileName is the sidecar beamsplitter writes next to its generated outputs to remember the
+// hash it last emitted for each definition.
+const cacheFileName = ".beamsplitter-cache"
+
+// regenerationCache is the on-disk shape of cacheFileName: fully qualified definition name to the
+// content hash (see hashDefinition) that was emitted for it last time.
+type regenerationCache map[string]string
+
+// loadCache reads cacheFileName from outputFolder. A missing or unreadable cache is treated as
+// empty so the first run after adding this feature regenerates everything, exactly like a cold
+// gofmt/build cache.
+func loadCache(outputFolder string) regenerationCache {
+	cache := regenerationCache{}
+	data, err := os.ReadFile(filepath.Join(outputFolder, cacheFileName))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return regenerationCache{}
+	}
+	return cache
+}
+
+// saveCache writes cache to cacheFileName in outputFolder.
+func saveCache(outputFolder string, cache regenerationCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputFolder, cacheFileName), data, 0644)
+}
+
+// changedDefinitions compares the current hash of each definition against cache and returns the
+// set of fully qualified names that need to be regenerated: anything with a new or changed hash,
+// plus (via transitiveDependents) anything that depends on one of those.
+func changedDefinitions(definitions []TypeDefinition, graph dependencyGraph, cache regenerationCache) map[string]bool {
+	changed := map[string]bool{}
+	for _, d := range definitions {
+		name := definitionName(d)
+		if cache[name] != hashDefinition(d) {
+			changed[name] = true
+		}
+	}
+	return transitiveDependents(graph, changed)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "testing"
+
+func TestFindCycleAcyclicGraph(t *testing.T) {
+	graph := dependencyGraph{
+		"A": {"B"},
+		"B": {"C"},
+		"C": nil,
+	}
+	if path := findCycle(graph); path != nil {
+		t.Errorf("findCycle() = %v, want nil for an acyclic graph", path)
+	}
+}
+
+func TestFindCycleDetectsCycle(t *testing.T) {
+	graph := dependencyGraph{
+		"A": {"B"},
+		"B": {"C"},
+		"C": {"A"},
+	}
+	path := findCycle(graph)
+	if path == nil {
+		t.Fatal("findCycle() = nil, want a detected cycle")
+	}
+	if path[0] != path[len(path)-1] {
+		t.Errorf("findCycle() = %v, want a path that starts and ends on the same node", path)
+	}
+}
+
+func TestFindCycleSelfReference(t *testing.T) {
+	graph := dependencyGraph{"A": {"A"}}
+	if path := findCycle(graph); path == nil {
+		t.Error("findCycle() = nil, want a cycle for a self-referencing node")
+	}
+}
+
+func TestTransitiveDependents(t *testing.T) {
+	// A depends on B, B depends on C: a change to C should mark B and A as dependents too.
+	graph := dependencyGraph{
+		"A": {"B"},
+		"B": {"C"},
+		"C": nil,
+	}
+	dependents := transitiveDependents(graph, map[string]bool{"C": true})
+	for _, name := range []string{"A", "B", "C"} {
+		if !dependents[name] {
+			t.Errorf("transitiveDependents()[%q] = false, want true", name)
+		}
+	}
+}
+
+func TestTransitiveDependentsUnrelatedNodeUnaffected(t *testing.T) {
+	graph := dependencyGraph{
+		"A": {"B"},
+		"B": nil,
+		"X": nil,
+	}
+	dependents := transitiveDependents(graph, map[string]bool{"B": true})
+	if dependents["X"] {
+		t.Error("transitiveDependents() marked an unrelated node as a dependent")
+	}
+}
+
+func TestSaveAndLoadCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	want := regenerationCache{"Engine": "abc123", "View": "def456"}
+	if err := saveCache(dir, want); err != nil {
+		t.Fatalf("saveCache() error = %v", err)
+	}
+
+	got := loadCache(dir)
+	if len(got) != len(want) {
+		t.Fatalf("loadCache() = %v, want %v", got, want)
+	}
+	for name, hash := range want {
+		if got[name] != hash {
+			t.Errorf("loadCache()[%q] = %q, want %q", name, got[name], hash)
+		}
+	}
+}
+
+func TestLoadCacheMissingFileIsEmpty(t *testing.T) {
+	dir := t.TempDir()
+	cache := loadCache(dir)
+	if len(cache) != 0 {
+		t.Errorf("loadCache() = %v, want an empty cache when no file exists", cache)
+	}
+}
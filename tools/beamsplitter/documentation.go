@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DocParam is one @param entry scraped from a Doxygen comment block.
+type DocParam struct {
+	Name string
+	Desc string
+}
+
+// Documentation is the structured form of a C++ `///` or `/** */` comment block. The parser
+// populates this once per TypeDefinition/StructDefinition/EnumDefinition (and per field/enumerator)
+// so that every emitter can render the same source comment in its own doc-comment dialect instead
+// of each one re-parsing the raw C++ comment text.
+type Documentation struct {
+	Summary    string
+	Params     []DocParam
+	Returns    string
+	Deprecated string
+}
+
+var (
+	tripleSlashPattern = regexp.MustCompile(`^\s*///\s?`)
+	blockStartPattern  = regexp.MustCompile(`^\s*/\*\*\s?`)
+	blockLinePattern   = regexp.MustCompile(`^\s*\*\s?`)
+	blockEndPattern    = regexp.MustCompile(`\s*\*/\s*$`)
+	paramTagPattern    = regexp.MustCompile(`^@param\s+(\S+)\s*(.*)$`)
+	returnsTagPattern  = regexp.MustCompile(`^@returns?\s+(.*)$`)
+	deprecatedPattern  = regexp.MustCompile(`^@deprecated\s*(.*)$`)
+)
+
+// ParseDocumentation turns a raw `///`-style or `/** */`-style C++ comment block into a
+// Documentation value. Lines that don't start with a recognized Doxygen tag are appended to
+// Summary. Unrecognized input (no comment markers at all) is treated as a bare summary so callers
+// can pass either a fully-formed comment or a plain one-line description.
+func ParseDocumentation(raw string) Documentation {
+	var doc Documentation
+	var summary []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		switch {
+		case tripleSlashPattern.MatchString(line):
+			line = tripleSlashPattern.ReplaceAllString(line, "")
+		case blockStartPattern.MatchString(line):
+			line = blockStartPattern.ReplaceAllString(line, "")
+			line = blockEndPattern.ReplaceAllString(line, "")
+		case blockLinePattern.MatchString(line):
+			// blockEndPattern must run first: a close-only line like " */" also matches
+			// blockLinePattern's leading "* ", and stripping that first would leave a bare "/"
+			// that blockEndPattern (which requires the literal "*/") can no longer match.
+			line = blockEndPattern.ReplaceAllString(line, "")
+			line = blockLinePattern.ReplaceAllString(line, "")
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := paramTagPattern.FindStringSubmatch(line); m != nil {
+			doc.Params = append(doc.Params, DocParam{Name: m[1], Desc: m[2]})
+			continue
+		}
+		if m := returnsTagPattern.FindStringSubmatch(line); m != nil {
+			doc.Returns = m[1]
+			continue
+		}
+		if m := deprecatedPattern.FindStringSubmatch(line); m != nil {
+			doc.Deprecated = m[1]
+			continue
+		}
+		summary = append(summary, line)
+	}
+
+	doc.Summary = strings.Join(summary, " ")
+	return doc
+}
+
+// WrapText wraps s into lines no longer than width, breaking on word boundaries. It never splits a
+// single word even if that word exceeds width.
+func WrapText(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	lines = append(lines, line)
+	return lines
+}
+
+// knownTypeLink, when non-empty for typename, resolves a bare C++ type name (as it would appear in
+// an `@param` or prose) to its reference page under filament.dev. Populated from the set of
+// StructDefinition/EnumDefinition names seen by the current emitter run.
+type linkResolver struct {
+	baseURL string
+	known   map[string]string
+}
+
+func newLinkResolver(baseURL string, known map[string]string) *linkResolver {
+	return &linkResolver{baseURL: baseURL, known: known}
+}
+
+// buildDocPaths maps every struct/enum name beamsplitter parsed in this run to the relative path
+// of its filament.dev reference page, for use as the known table passed to newLinkResolver.
+func buildDocPaths(definitions []TypeDefinition) map[string]string {
+	paths := make(map[string]string, len(definitions))
+	for _, d := range definitions {
+		name := definitionName(d)
+		if name == "" {
+			continue
+		}
+		paths[name] = strings.ToLower(name) + ".html"
+	}
+	return paths
+}
+
+// resolve rewrites any `{@link Name}`-style reference in text into a Markdown/HTML link pointing at
+// filament.dev, using known to map a short type name to its relative doc path. References to
+// unknown names are left untouched so a typo doesn't produce a dead link silently.
+var linkTagPattern = regexp.MustCompile(`\{@link\s+([\w:]+)\}`)
+
+func (r *linkResolver) resolve(text string) string {
+	return linkTagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		name := linkTagPattern.FindStringSubmatch(tag)[1]
+		path, ok := r.known[name]
+		if !ok {
+			return tag
+		}
+		return "[" + name + "](" + r.baseURL + "/" + path + ")"
+	})
+}
+
+// resolveJavadoc rewrites a `{@link Name}` reference for the Java backend: when Name is one of the
+// other types beamsplitter generated in this run (present in r.known, which is built from this
+// run's own definitions) it becomes `{@link packagePrefix.Name}` so javadoc/doclava cross-links the
+// two generated classes directly; otherwise it falls back to an `<a href>` pointing at the
+// filament.dev reference page, same as the JS/TS docs.
+func (r *linkResolver) resolveJavadoc(text string, packagePrefix string) string {
+	return linkTagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		name := linkTagPattern.FindStringSubmatch(tag)[1]
+		if _, ok := r.known[name]; ok {
+			return "{@link " + packagePrefix + "." + name + "}"
+		}
+		return "<a href=\"" + r.baseURL + "/" + strings.ToLower(name) + ".html\">" + name + "</a>"
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocumentationTripleSlash(t *testing.T) {
+	doc := ParseDocumentation("/// Computes the thing.\n/// @param count how many things\n/// @return the result\n/// @deprecated use computeThing2 instead")
+	if doc.Summary != "Computes the thing." {
+		t.Errorf("Summary = %q, want %q", doc.Summary, "Computes the thing.")
+	}
+	want := []DocParam{{Name: "count", Desc: "how many things"}}
+	if !reflect.DeepEqual(doc.Params, want) {
+		t.Errorf("Params = %v, want %v", doc.Params, want)
+	}
+	if doc.Returns != "the result" {
+		t.Errorf("Returns = %q, want %q", doc.Returns, "the result")
+	}
+	if doc.Deprecated != "use computeThing2 instead" {
+		t.Errorf("Deprecated = %q, want %q", doc.Deprecated, "use computeThing2 instead")
+	}
+}
+
+func TestParseDocumentationBlockComment(t *testing.T) {
+	doc := ParseDocumentation("/**\n * Line one.\n * Line two.\n */")
+	if doc.Summary != "Line one. Line two." {
+		t.Errorf("Summary = %q, want %q", doc.Summary, "Line one. Line two.")
+	}
+}
+
+func TestParseDocumentationPlainText(t *testing.T) {
+	doc := ParseDocumentation("Just a bare description.")
+	if doc.Summary != "Just a bare description." {
+		t.Errorf("Summary = %q, want %q", doc.Summary, "Just a bare description.")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  []string
+	}{
+		{"empty", "", 10, nil},
+		{"fits on one line", "short text", 20, []string{"short text"}},
+		{"wraps at word boundary", "one two three four", 9, []string{"one two", "three", "four"}},
+		{"never splits an overlong word", "supercalifragilistic word", 5, []string{"supercalifragilistic", "word"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := WrapText(test.s, test.width)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("WrapText(%q, %d) = %v, want %v", test.s, test.width, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinkResolverResolve(t *testing.T) {
+	r := newLinkResolver("https://filament.dev/docs", map[string]string{"Engine": "engine.html"})
+	got := r.resolve("See {@link Engine} for details. {@link Unknown} is left alone.")
+	want := "See [Engine](https://filament.dev/docs/engine.html) for details. {@link Unknown} is left alone."
+	if got != want {
+		t.Errorf("resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestLinkResolverResolveJavadoc(t *testing.T) {
+	r := newLinkResolver("https://google.github.io/filament/javadoc", map[string]string{"Engine": "engine.html"})
+
+	got := r.resolveJavadoc("See {@link Engine}.", "com.google.android.filament")
+	want := "See {@link com.google.android.filament.Engine}."
+	if got != want {
+		t.Errorf("resolveJavadoc() generated-type case = %q, want %q", got, want)
+	}
+
+	got = r.resolveJavadoc("See {@link Unrelated}.", "com.google.android.filament")
+	want = `See <a href="https://google.github.io/filament/javadoc/unrelated.html">Unrelated</a>.`
+	if got != want {
+		t.Errorf("resolveJavadoc() external case = %q, want %q", got, want)
+	}
+}
@@ -26,12 +26,55 @@ import (
 	"text/template"
 )
 
-func createJavaCodeGenerator() func(*os.File, string, Scope) {
+// javadocWidth is the column at which wrapped Javadoc lines break, matching the project's .clang-format
+// line length so generated Java reads consistently with the hand-written sources it sits next to.
+const javadocWidth = 100
+
+// javadocPackage is the Java package generated classes live in; it's what {@link} references
+// resolve against so javadoc/doclava can cross-link between generated types.
+const javadocPackage = "com.google.android.filament"
+
+func createJavaCodeGenerator(definitions []TypeDefinition) func(*os.File, string, Scope) {
+	// Shared with createJsCodeGenerator so that a math:: vector field resolves to the same flavor
+	// (and thus the same shape of mapping) regardless of which language backend is emitting it.
+	flavors := newClassifier(buildKnownSymbols(definitions))
+	links := newLinkResolver("https://google.github.io/filament/javadoc", buildDocPaths(definitions))
 	customExtensions := template.FuncMap{
+		// javadoc renders a raw `///` or `/** */` C++ comment block (desc) as real Javadoc, indented
+		// to depth levels of 4 spaces. @param/@returns/@deprecated tags parsed out of desc are
+		// re-emitted as their Javadoc equivalents instead of being folded into the prose, and any
+		// {@link Name} reference is resolved across the generated types (see resolveJavadoc).
 		"javadoc": func(desc string, depth int) string {
-			return "// " + desc
+			doc := ParseDocumentation(desc)
+			doc.Summary = links.resolveJavadoc(doc.Summary, javadocPackage)
+			indent := strings.Repeat("    ", depth)
+			var b strings.Builder
+			b.WriteString(indent + "/**\n")
+			for _, line := range WrapText(doc.Summary, javadocWidth-len(indent)-3) {
+				b.WriteString(indent + " * " + line + "\n")
+			}
+			if len(doc.Params) > 0 || doc.Returns != "" || doc.Deprecated != "" {
+				b.WriteString(indent + " *\n")
+			}
+			for _, param := range doc.Params {
+				b.WriteString(indent + " * @param " + param.Name + " " + links.resolveJavadoc(param.Desc, javadocPackage) + "\n")
+			}
+			if doc.Returns != "" {
+				b.WriteString(indent + " * @return " + links.resolveJavadoc(doc.Returns, javadocPackage) + "\n")
+			}
+			if doc.Deprecated != "" {
+				b.WriteString(indent + " * @deprecated " + doc.Deprecated + "\n")
+			}
+			b.WriteString(indent + " */")
+			return b.String()
 		},
+		// java_type maps a math:: vector/matrix field to its Java binding class (e.g. "math::float3"
+		// -> "Float3"); everything else passes through unchanged, same as before the classifier.
 		"java_type": func(cpptype string) string {
+			if flavor, confidence := flavors.classify(cpptype); confidence >= 0.5 && flavor == FlavorMathVector {
+				bare := strings.TrimPrefix(cpptype, "math::")
+				return strings.ToUpper(bare[:1]) + bare[1:]
+			}
 			return cpptype
 		},
 		"java_value": func(cppval string) string {
@@ -40,7 +83,7 @@ func createJavaCodeGenerator() func(*os.File, string, Scope) {
 	}
 
 	templ := template.New("beamsplitter").Funcs(customExtensions)
-	templ = template.Must(templ.ParseFiles("java.template"))
+	templ = template.Must(templ.ParseFiles(resolveTemplatePath("java.template", activeTemplateOverlayDir)))
 	return func(file *os.File, section string, definition Scope) {
 		err := templ.ExecuteTemplate(file, "CppStructReader", definition)
 		if err != nil {
@@ -52,6 +95,7 @@ func createJavaCodeGenerator() func(*os.File, string, Scope) {
 func EditJava(definitions []Scope, classname string, folder string) {
 	path := filepath.Join(folder, classname+".java")
 	var codelines []string
+	var oldTail []string
 	{
 		sourceFile, err := os.Open(path)
 		if err != nil {
@@ -64,7 +108,11 @@ func EditJava(definitions []Scope, classname string, folder string) {
 			codeline := lineScanner.Text()
 			if strings.Contains(codeline, CodelineMarker) {
 				foundMarker = true
-				break
+				continue
+			}
+			if foundMarker {
+				oldTail = append(oldTail, codeline)
+				continue
 			}
 			codelines = append(codelines, codeline)
 		}
@@ -72,6 +120,42 @@ func EditJava(definitions []Scope, classname string, folder string) {
 			log.Fatal("Unable to find marker line in Java file.")
 		}
 	}
+
+	// definitions also satisfy TypeDefinition (the concrete *StructDefinition/*EnumDefinition types
+	// implement both), so the same dependency graph and content-hash cache used by EmitJavaScript
+	// drives per-definition region splicing here too, keyed by this class's own folder/classname.
+	typed := make([]TypeDefinition, len(definitions))
+	for i, d := range definitions {
+		typed[i] = d.(TypeDefinition)
+	}
+	SortDefinitions(typed)
+	graph, err := BuildDependencyGraph(typed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cache := loadCache(folder)
+	changed := changedDefinitions(typed, graph, cache)
+	defer func() {
+		for _, d := range typed {
+			cache[definitionName(d)] = hashDefinition(d)
+		}
+		if err := saveCache(folder, cache); err != nil {
+			log.Println("warning: failed to write " + cacheFileName + ": " + err.Error())
+		}
+	}()
+
+	oldRegions := extractRegions(strings.Join(oldTail, "\n"))
+	generate := createJavaCodeGenerator(typed)
+	spliced := renderRegions(typed, changed, oldRegions, func(d TypeDefinition) string {
+		switch d.(type) {
+		case *StructDefinition:
+			return renderScopeToString(generate, "Struct", d.(Scope))
+		case *EnumDefinition:
+			return renderScopeToString(generate, "Enum", d.(Scope))
+		}
+		return ""
+	})
+
 	file, err := os.Create(path)
 	if err != nil {
 		log.Fatal(err)
@@ -83,16 +167,7 @@ func EditJava(definitions []Scope, classname string, folder string) {
 		file.WriteString("\n")
 	}
 	file.WriteString("    // " + CodelineMarker + "\n")
-
-	generate := createJavaCodeGenerator()
-	for _, definition := range definitions {
-		switch definition.(type) {
-		case *StructDefinition:
-			generate(file, "Struct", definition)
-		case *EnumDefinition:
-			generate(file, "Enum", definition)
-		}
-	}
+	file.WriteString(spliced)
 
 	file.WriteString("}\n")
 }
@@ -28,7 +28,10 @@ import (
 
 // Returns a templating function that automatically checks for fatal errors. The returned function
 // takes an output stream, a template name to invoke, and a template context object.
-func createJsCodeGenerator(namespace string) func(*os.File, string, TypeDefinition) {
+// tsdocWidth is the column at which wrapped JSDoc lines break in the generated filament.d.ts.
+const tsdocWidth = 100
+
+func createJsCodeGenerator(namespace string, definitions []TypeDefinition) func(*os.File, string, TypeDefinition) {
 	jsPrefix := ""
 	classPrefix := ""
 	cppPrefix := ""
@@ -44,12 +47,26 @@ func createJsCodeGenerator(namespace string) func(*os.File, string, TypeDefiniti
 	// convention is to use $ for the scoping delimiter, which is a legal symbol character in JS.
 	// However we still use . to separate the enum value from the enum type, because emscripten has
 	// first-class support for class enums.
+	//
+	// A shared classifier scores each token against a handful of pattern-based candidates (math
+	// vector constructors, enum-qualified values, preprocessor tokens, etc), plus the known-symbol
+	// table harvested from definitions itself (every struct/enum name and enumerator). Low-confidence
+	// or tied matches fall back to the checks below, which is why tstype/qualifiedvalue still
+	// special-case the handful of types that need to stay exact regardless of the classifier's guess.
+	flavors := newClassifier(buildKnownSymbols(definitions))
+	links := newLinkResolver("https://filament.dev/docs", buildDocPaths(definitions))
 	customExtensions := template.FuncMap{
 		"qualifiedtype": func(typename string) string {
 			typename = strings.ReplaceAll(typename, "::", "$")
 			return typename
 		},
 		"qualifiedvalue": func(name string) string {
+			// A bare preprocessor token (no C++ scope to flatten) is passed through untouched
+			// instead of running it through the "::" replacement logic below, which would be a
+			// no-op anyway but is skipped here to make that explicit rather than incidental.
+			if flavor, confidence := flavors.classify(name); flavor == FlavorPreprocessorToken && confidence >= 0.5 {
+				return name
+			}
 			count := strings.Count(name, "::")
 			if count > 0 {
 				name = "Filament." + jsPrefix + name
@@ -59,9 +76,6 @@ func createJsCodeGenerator(namespace string) func(*os.File, string, TypeDefiniti
 			return name
 		},
 		"tstype": func(cpptype string) string {
-			if strings.HasPrefix(cpptype, "math::") {
-				return cpptype[6:]
-			}
 			switch cpptype {
 			case "float", "uint8_t", "uint32_t", "uint16_t":
 				return "number"
@@ -72,15 +86,73 @@ func createJsCodeGenerator(namespace string) func(*os.File, string, TypeDefiniti
 			case "LinearColor":
 				return "float3"
 			}
+			if flavor, confidence := flavors.classify(cpptype); confidence >= 0.5 {
+				switch flavor {
+				case FlavorMathVector:
+					return strings.TrimPrefix(cpptype, "math::")
+				case FlavorEnumValue, FlavorScopedSymbol:
+					return jsPrefix + strings.ReplaceAll(cpptype, "::", "$")
+				}
+			}
+			// Fallback for anything the classifier couldn't place with confidence, preserving the
+			// pre-classifier behavior.
+			if strings.HasPrefix(cpptype, "math::") {
+				return cpptype[6:]
+			}
 			return jsPrefix + strings.ReplaceAll(cpptype, "::", "$")
 		},
 		"jsprefix":    func() string { return jsPrefix },
 		"cprefix":     func() string { return cppPrefix },
 		"classprefix": func() string { return classPrefix },
+		// tsdoc renders a raw `///` or `/** */` C++ comment block as JSDoc for TsStruct/TsEnum,
+		// wrapped at tsdocWidth columns with @param/@returns/@deprecated preserved, and any
+		// {@link Name} reference resolved to a filament.dev link (see java.go's javadoc, which
+		// does the same thing for the Java backend).
+		"tsdoc": func(desc string, depth int) string {
+			doc := ParseDocumentation(desc)
+			doc.Summary = links.resolve(doc.Summary)
+			indent := strings.Repeat("  ", depth)
+			var b strings.Builder
+			b.WriteString(indent + "/**\n")
+			for _, line := range WrapText(doc.Summary, tsdocWidth-len(indent)-3) {
+				b.WriteString(indent + " * " + line + "\n")
+			}
+			if len(doc.Params) > 0 || doc.Returns != "" || doc.Deprecated != "" {
+				b.WriteString(indent + " *\n")
+			}
+			for _, param := range doc.Params {
+				b.WriteString(indent + " * @param " + param.Name + " " + links.resolve(param.Desc) + "\n")
+			}
+			if doc.Returns != "" {
+				b.WriteString(indent + " * @returns " + links.resolve(doc.Returns) + "\n")
+			}
+			if doc.Deprecated != "" {
+				b.WriteString(indent + " * @deprecated " + doc.Deprecated + "\n")
+			}
+			b.WriteString(indent + " */")
+			return b.String()
+		},
+		// cppdoc renders the same Doxygen comment block as plain "//" line comments ahead of a
+		// EMSCRIPTEN_BINDINGS registration in jsbindings_generated.cpp, so the doc text the parser
+		// picked up off the C++ header is still visible next to the generated binding, not just in
+		// the JS/TS/Java output. {@link Name} references are resolved the same way tsdoc does.
+		"cppdoc": func(desc string, depth int) string {
+			doc := ParseDocumentation(desc)
+			doc.Summary = links.resolve(doc.Summary)
+			indent := strings.Repeat("  ", depth)
+			var b strings.Builder
+			for _, line := range WrapText(doc.Summary, tsdocWidth-len(indent)-3) {
+				b.WriteString(indent + "// " + line + "\n")
+			}
+			if doc.Deprecated != "" {
+				b.WriteString(indent + "// @deprecated " + doc.Deprecated + "\n")
+			}
+			return strings.TrimSuffix(b.String(), "\n")
+		},
 	}
 
 	templ := template.New("beamsplitter").Funcs(customExtensions)
-	templ = template.Must(templ.ParseFiles("javascript.template"))
+	templ = template.Must(templ.ParseFiles(resolveTemplatePath("javascript.template", activeTemplateOverlayDir)))
 	return func(file *os.File, section string, definition TypeDefinition) {
 		err := templ.ExecuteTemplate(file, section, definition)
 		if err != nil {
@@ -89,71 +161,78 @@ func createJsCodeGenerator(namespace string) func(*os.File, string, TypeDefiniti
 	}
 }
 
-func EmitJavaScript(definitions []TypeDefinition, namespace string, outputFolder string) {
-	generate := createJsCodeGenerator(namespace)
-	{
-		path := filepath.Join(outputFolder, "jsbindings_generated.cpp")
-		file, err := os.Create(path)
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer file.Close()
-		defer fmt.Println("Generated " + path)
-
-		generate(file, "JsBindingsHeader", nil)
+// emitJsFile regenerates one of EmitJavaScript's three output files, reusing the previously
+// emitted region (see regions.go) for any definition changedDefinitions didn't flag so that
+// editing one struct's fields only moves the lines inside that struct's region.
+func emitJsFile(
+	path string,
+	header, footer string,
+	definitions []TypeDefinition,
+	perDefinitionSection string,
+	wantKind func(TypeDefinition) bool,
+	changed map[string]bool,
+	generate func(*os.File, string, TypeDefinition),
+) {
+	old, _ := os.ReadFile(path)
+	oldRegions := extractRegions(string(old))
 
-		for _, definition := range definitions {
-			switch definition.(type) {
-			case *StructDefinition:
-				generate(file, "JsBindingsStruct", definition)
-			}
+	var body []TypeDefinition
+	for _, d := range definitions {
+		if wantKind(d) {
+			body = append(body, d)
 		}
-		generate(file, "JsBindingsFooter", nil)
 	}
-	{
-		path := filepath.Join(outputFolder, "jsenums_generated.cpp")
-		file, err := os.Create(path)
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer file.Close()
-		defer fmt.Println("Generated " + path)
+	spliced := renderRegions(body, changed, oldRegions, func(d TypeDefinition) string {
+		return renderDefinitionToString(generate, perDefinitionSection, d)
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	defer fmt.Println("Generated " + path)
 
-		generate(file, "JsEnumsHeader", nil)
+	generate(file, header, nil)
+	file.WriteString(spliced)
+	generate(file, footer, nil)
+}
 
-		for _, definition := range definitions {
-			switch definition.(type) {
-			case *EnumDefinition:
-				generate(file, "JsEnum", definition)
-			}
-		}
+func EmitJavaScript(definitions []TypeDefinition, namespace string, outputFolder string) {
+	SortDefinitions(definitions)
+	generate := createJsCodeGenerator(namespace, definitions)
 
-		generate(file, "JsEnumsFooter", nil)
+	// The dependency graph and content hashes tell us which definitions actually changed since the
+	// last run (and, transitively, which dependents need to follow); emitJsFile uses that set to
+	// reuse each unchanged definition's previously emitted region verbatim instead of re-rendering
+	// every definition on every run. The cache itself is loaded but deliberately not saved here:
+	// EditTypeScript runs against the same outputFolder right after EmitJavaScript and needs to see
+	// this same pre-run snapshot to make its own splicing decisions, so whichever of the two runs
+	// last is responsible for persisting the refreshed hashes (see EditTypeScript).
+	graph, err := BuildDependencyGraph(definitions)
+	if err != nil {
+		log.Fatal(err)
 	}
-	{
-		path := filepath.Join(outputFolder, "extensions_generated.js")
-		file, err := os.Create(path)
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer file.Close()
-		defer fmt.Println("Generated " + path)
+	cache := loadCache(outputFolder)
+	changed := changedDefinitions(definitions, graph, cache)
+	fmt.Printf("%d of %d definitions changed since last run\n", len(changed), len(definitions))
 
-		generate(file, "JsExtensionsHeader", nil)
+	isStruct := func(d TypeDefinition) bool { _, ok := d.(*StructDefinition); return ok }
+	isEnum := func(d TypeDefinition) bool { _, ok := d.(*EnumDefinition); return ok }
 
-		for _, definition := range definitions {
-			switch definition.(type) {
-			case *StructDefinition:
-				generate(file, "JsExtension", definition)
-			}
-		}
-		generate(file, "JsExtensionsFooter", nil)
-	}
+	emitJsFile(filepath.Join(outputFolder, "jsbindings_generated.cpp"),
+		"JsBindingsHeader", "JsBindingsFooter", definitions, "JsBindingsStruct", isStruct, changed, generate)
+	emitJsFile(filepath.Join(outputFolder, "jsenums_generated.cpp"),
+		"JsEnumsHeader", "JsEnumsFooter", definitions, "JsEnum", isEnum, changed, generate)
+	emitJsFile(filepath.Join(outputFolder, "extensions_generated.js"),
+		"JsExtensionsHeader", "JsExtensionsFooter", definitions, "JsExtension", isStruct, changed, generate)
 }
 
 func EditTypeScript(definitions []TypeDefinition, namespace string, folder string) {
+	SortDefinitions(definitions)
 	path := filepath.Join(folder, "filament.d.ts")
 	var codelines []string
+	var oldTail []string
 	{
 		sourceFile, err := os.Open(path)
 		if err != nil {
@@ -166,7 +245,11 @@ func EditTypeScript(definitions []TypeDefinition, namespace string, folder strin
 			codeline := lineScanner.Text()
 			if strings.Contains(codeline, CodelineMarker) {
 				foundMarker = true
-				break
+				continue
+			}
+			if foundMarker {
+				oldTail = append(oldTail, codeline)
+				continue
 			}
 			codelines = append(codelines, codeline)
 		}
@@ -175,6 +258,37 @@ func EditTypeScript(definitions []TypeDefinition, namespace string, folder strin
 		}
 	}
 
+	// Reuse the same pre-run cache snapshot EmitJavaScript read (see the comment there), so that a
+	// struct changed on this run is regenerated in both jsbindings_generated.cpp and here, and
+	// anything unchanged keeps its previously emitted region in both places too. This call is the
+	// one that persists the refreshed hashes, since it runs last.
+	graph, err := BuildDependencyGraph(definitions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cache := loadCache(folder)
+	changed := changedDefinitions(definitions, graph, cache)
+	defer func() {
+		for _, d := range definitions {
+			cache[definitionName(d)] = hashDefinition(d)
+		}
+		if err := saveCache(folder, cache); err != nil {
+			log.Println("warning: failed to write " + cacheFileName + ": " + err.Error())
+		}
+	}()
+
+	oldRegions := extractRegions(strings.Join(oldTail, "\n"))
+	generate := createJsCodeGenerator(namespace, definitions)
+	spliced := renderRegions(definitions, changed, oldRegions, func(d TypeDefinition) string {
+		switch d.(type) {
+		case *StructDefinition:
+			return renderDefinitionToString(generate, "TsStruct", d)
+		case *EnumDefinition:
+			return renderDefinitionToString(generate, "TsEnum", d)
+		}
+		return ""
+	})
+
 	file, err := os.Create(path)
 	if err != nil {
 		log.Fatal(err)
@@ -187,14 +301,5 @@ func EditTypeScript(definitions []TypeDefinition, namespace string, folder strin
 		file.WriteString("\n")
 	}
 	file.WriteString("// " + CodelineMarker + "\n")
-
-	generate := createJsCodeGenerator(namespace)
-	for _, definition := range definitions {
-		switch definition.(type) {
-		case *StructDefinition:
-			generate(file, "TsStruct", definition)
-		case *EnumDefinition:
-			generate(file, "TsEnum", definition)
-		}
-	}
+	file.WriteString(spliced)
 }
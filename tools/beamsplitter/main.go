@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	emitterFlag := flag.String("emitter", "js,java,python", "comma-separated emitters to run (see -list-emitters)")
+	namespaceFlag := flag.String("namespace", "filament", "C++ namespace the parsed headers live under")
+	classNameFlag := flag.String("classname", "Filament", "output class/file stem for the java emitter")
+	outputFlag := flag.String("output", ".", "folder the emitter writes/edits its generated files in")
+	overlayFlag := flag.String("template-overlay", "", "directory whose *.template files shadow the built-in ones")
+	checkFlag := flag.Bool("check", false, "verify generated files are up to date instead of regenerating them")
+	listFlag := flag.Bool("list-emitters", false, "print the names of every registered emitter and exit")
+	flag.Parse()
+
+	if *listFlag {
+		for _, e := range RegisteredEmitters() {
+			fmt.Println(e.Name())
+		}
+		return
+	}
+
+	if *overlayFlag != "" {
+		SetTemplateOverlayDir(*overlayFlag)
+	}
+
+	// Parse is defined alongside TypeDefinition/StructDefinition/EnumDefinition/Scope/CodelineMarker,
+	// outside this slice of the tree; it turns the parsed C++ headers into the TypeDefinition list
+	// every Emitter.Generate/Verify* call below consumes.
+	definitions := Parse(flag.Args())
+
+	cfg := EmitterConfig{
+		Namespace:          *namespaceFlag,
+		ClassName:          *classNameFlag,
+		OutputFolder:       *outputFlag,
+		TemplateOverlayDir: *overlayFlag,
+	}
+
+	drifted := false
+	for _, name := range strings.Split(*emitterFlag, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e := LookupEmitter(name)
+		if e == nil {
+			log.Fatalf("unknown emitter %q, see -list-emitters", name)
+		}
+
+		if !*checkFlag {
+			if err := e.Generate(definitions, cfg); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+
+		for _, err := range verifyEmitter(e, definitions, cfg) {
+			fmt.Fprintln(os.Stderr, err)
+			drifted = true
+		}
+	}
+
+	if *checkFlag && drifted {
+		os.Exit(1)
+	}
+}
+
+// verifyEmitter dispatches to the Verify* counterpart of an Emitter's Generate, the same way main
+// dispatches Generate itself. New emitters that don't have a Verify* counterpart yet (e.g. Python)
+// are reported as a single drift so -check still fails loudly instead of silently skipping them.
+func verifyEmitter(e Emitter, definitions []TypeDefinition, cfg EmitterConfig) []error {
+	switch e.Name() {
+	case "js":
+		var errs []error
+		errs = append(errs, VerifyJavaScript(definitions, cfg.Namespace, cfg.OutputFolder)...)
+		if err := VerifyTypeScript(definitions, cfg.Namespace, cfg.OutputFolder); err != nil {
+			errs = append(errs, err)
+		}
+		return errs
+	case "java":
+		if err := VerifyJava(definitions, cfg.ClassName, cfg.OutputFolder); err != nil {
+			return []error{err}
+		}
+		return nil
+	default:
+		return []error{fmt.Errorf("emitter %q has no --check support yet", e.Name())}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Returns a templating function that automatically checks for fatal errors, mirroring
+// createJsCodeGenerator. The returned function takes an output stream, a template name to invoke,
+// and a template context object.
+func createPyCodeGenerator(namespace string) func(*os.File, string, TypeDefinition) {
+	pyPrefix := ""
+	if namespace != "" {
+		pyPrefix = namespace + "_"
+	}
+	// These template extensions transmogrify C++ symbols and value literals into Python. pybind11
+	// exposes scoped C++ enums directly as enum.IntEnum subclasses, so unlike the JS path there's no
+	// need to flatten "::" into a scoping delimiter here.
+	customExtensions := template.FuncMap{
+		"qualifiedvalue": func(name string) string {
+			return strings.ReplaceAll(name, "::", ".")
+		},
+		"pytype": func(cpptype string) string {
+			if strings.HasPrefix(cpptype, "math::") {
+				return "numpy.ndarray"
+			}
+			switch cpptype {
+			case "float":
+				return "float"
+			case "uint8_t", "uint32_t", "uint16_t":
+				return "int"
+			case "bool":
+				return "bool"
+			case "LinearColorA":
+				return "numpy.ndarray"
+			case "LinearColor":
+				return "numpy.ndarray"
+			}
+			return pyPrefix + strings.ReplaceAll(cpptype, "::", "_")
+		},
+		"pydtype": func(cpptype string) string {
+			if !strings.HasPrefix(cpptype, "math::") {
+				return ""
+			}
+			switch cpptype {
+			case "math::float2", "math::float3", "math::float4",
+				"math::mat3f", "math::mat4f", "math::quatf":
+				return "numpy.float32"
+			}
+			return "numpy.float64"
+		},
+		"pyprefix": func() string { return pyPrefix },
+	}
+
+	templ := template.New("beamsplitter").Funcs(customExtensions)
+	templ = template.Must(templ.ParseFiles(resolveTemplatePath("python.template", activeTemplateOverlayDir)))
+	return func(file *os.File, section string, definition TypeDefinition) {
+		err := templ.ExecuteTemplate(file, section, definition)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+}
+
+// EmitPython generates pybind11-compatible C++ bindings (pybindings_generated.cpp) and a matching
+// .pyi stub file for type checkers, following the same per-definition pass structure as
+// EmitJavaScript.
+func EmitPython(definitions []TypeDefinition, namespace string, outputFolder string) {
+	SortDefinitions(definitions)
+	generate := createPyCodeGenerator(namespace)
+	{
+		path := filepath.Join(outputFolder, "pybindings_generated.cpp")
+		file, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		defer fmt.Println("Generated " + path)
+
+		generate(file, "PyBindingsHeader", nil)
+
+		for _, definition := range definitions {
+			switch definition.(type) {
+			case *StructDefinition:
+				generate(file, "PyStruct", definition)
+			case *EnumDefinition:
+				generate(file, "PyEnum", definition)
+			}
+		}
+		generate(file, "PyBindingsFooter", nil)
+	}
+	{
+		path := filepath.Join(outputFolder, namespace+".pyi")
+		file, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		defer fmt.Println("Generated " + path)
+
+		generate(file, "PyStubHeader", nil)
+
+		for _, definition := range definitions {
+			switch definition.(type) {
+			case *StructDefinition:
+				generate(file, "PyStructStub", definition)
+			case *EnumDefinition:
+				generate(file, "PyEnumStub", definition)
+			}
+		}
+		generate(file, "PyStubFooter", nil)
+	}
+	{
+		path := filepath.Join(outputFolder, "extensions_generated.py")
+		file, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		defer fmt.Println("Generated " + path)
+
+		generate(file, "PyExtensionsHeader", nil)
+
+		for _, definition := range definitions {
+			switch definition.(type) {
+			case *StructDefinition:
+				generate(file, "PyExtension", definition)
+			}
+		}
+		generate(file, "PyExtensionsFooter", nil)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+)
+
+// regionBeginPrefix/regionEndPrefix bracket the generated text for a single definition inside an
+// otherwise append-only generated file. They let EmitJavaScript/EditTypeScript/EditJava reuse the
+// exact bytes they emitted last time for any definition that changedDefinitions didn't flag,
+// instead of re-running the template (and thus touching the line) for every definition on every
+// run.
+const (
+	regionBeginPrefix = "// beamsplitter:region "
+	regionEndPrefix   = "// beamsplitter:endregion "
+)
+
+// extractRegions scans a previously generated file's contents and returns the verbatim text
+// (including its begin/end marker lines) keyed by definition name, so renderRegions can reuse it
+// for anything unchanged.
+func extractRegions(content string) map[string]string {
+	regions := map[string]string{}
+	if content == "" {
+		return regions
+	}
+	lines := strings.Split(content, "\n")
+	var name string
+	var buf []string
+	capturing := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, regionBeginPrefix):
+			name = strings.TrimPrefix(line, regionBeginPrefix)
+			buf = []string{line}
+			capturing = true
+		case strings.HasPrefix(line, regionEndPrefix):
+			if capturing {
+				buf = append(buf, line)
+				regions[name] = strings.Join(buf, "\n") + "\n"
+			}
+			capturing = false
+		case capturing:
+			buf = append(buf, line)
+		}
+	}
+	return regions
+}
+
+// renderRegions walks definitions in order and, for each one, either reuses its previously emitted
+// region verbatim (when it's absent from changed) or renders a fresh one via render. The returned
+// string is the full concatenated body to place between a file's static header and footer.
+func renderRegions(
+	definitions []TypeDefinition,
+	changed map[string]bool,
+	old map[string]string,
+	render func(d TypeDefinition) string,
+) string {
+	var b strings.Builder
+	for _, d := range definitions {
+		name := definitionName(d)
+		if existing, ok := old[name]; ok && !changed[name] {
+			b.WriteString(existing)
+			continue
+		}
+		b.WriteString(regionBeginPrefix + name + "\n")
+		b.WriteString(render(d))
+		b.WriteString(regionEndPrefix + name + "\n")
+	}
+	return b.String()
+}
+
+// renderDefinitionToString invokes a createJsCodeGenerator-style function for section/definition and
+// captures its output as a string, since it only knows how to write into an *os.File. A failure to
+// create or read back the temp file aborts the run via log.Fatal, the same as every other I/O path
+// in this codebase, rather than silently splicing empty content into a generated region.
+func renderDefinitionToString(generate func(*os.File, string, TypeDefinition), section string, definition TypeDefinition) string {
+	tmp, err := os.CreateTemp("", "beamsplitter-region-*")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	generate(tmp, section, definition)
+	tmp.Sync()
+	content, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(content)
+}
+
+// renderScopeToString is renderDefinitionToString for createJavaCodeGenerator, whose generate
+// function takes a Scope rather than a TypeDefinition.
+func renderScopeToString(generate func(*os.File, string, Scope), section string, definition Scope) string {
+	tmp, err := os.CreateTemp("", "beamsplitter-region-*")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	generate(tmp, section, definition)
+	tmp.Sync()
+	content, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(content)
+}
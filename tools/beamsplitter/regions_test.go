@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "testing"
+
+func TestExtractRegionsEmptyContent(t *testing.T) {
+	regions := extractRegions("")
+	if len(regions) != 0 {
+		t.Errorf("extractRegions(\"\") = %v, want empty", regions)
+	}
+}
+
+func TestExtractRegionsRoundTripsMarkedBlocks(t *testing.T) {
+	content := "" +
+		"// beamsplitter:region Engine\n" +
+		"class Engine {}\n" +
+		"// beamsplitter:endregion Engine\n" +
+		"// beamsplitter:region View\n" +
+		"class View {}\n" +
+		"// beamsplitter:endregion View\n"
+
+	regions := extractRegions(content)
+	if len(regions) != 2 {
+		t.Fatalf("extractRegions() found %d regions, want 2: %v", len(regions), regions)
+	}
+	if regions["Engine"] != "// beamsplitter:region Engine\nclass Engine {}\n// beamsplitter:endregion Engine\n" {
+		t.Errorf("extractRegions()[Engine] = %q", regions["Engine"])
+	}
+	if regions["View"] != "// beamsplitter:region View\nclass View {}\n// beamsplitter:endregion View\n" {
+		t.Errorf("extractRegions()[View] = %q", regions["View"])
+	}
+}
+
+func TestExtractRegionsIgnoresUnmarkedText(t *testing.T) {
+	content := "header text\n// beamsplitter:region Engine\nclass Engine {}\n// beamsplitter:endregion Engine\nfooter text\n"
+	regions := extractRegions(content)
+	if len(regions) != 1 {
+		t.Fatalf("extractRegions() found %d regions, want 1: %v", len(regions), regions)
+	}
+	if _, ok := regions["Engine"]; !ok {
+		t.Error("extractRegions() missing expected Engine region")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// EmitterConfig bundles the arguments every Emitter.Generate needs. TemplateOverlayDir is set from
+// the --template-overlay flag: when non-empty and it contains a file with the same name as one of
+// an emitter's TemplateFiles(), that overlay file is parsed instead of the built-in one next to the
+// beamsplitter binary, so downstream users can customize codegen without forking the repo.
+type EmitterConfig struct {
+	Namespace string
+	// ClassName is the output class/file stem for emitters whose generated file is named after a
+	// single class rather than the namespace (currently just "java", whose EditJava writes
+	// ClassName+".java"). Unused by jsEmitter/pyEmitter, which key their output filenames off
+	// Namespace instead.
+	ClassName          string
+	OutputFolder       string
+	TemplateOverlayDir string
+}
+
+// activeTemplateOverlayDir is consulted by createJsCodeGenerator/createJavaCodeGenerator/
+// createPyCodeGenerator so that --template-overlay works without changing the signature of every
+// existing Emit*/Edit* function (and thus without disturbing any caller outside this package).
+// jsEmitter/javaEmitter/pyEmitter set it from cfg.TemplateOverlayDir before delegating.
+var activeTemplateOverlayDir string
+
+// SetTemplateOverlayDir sets the directory whose files shadow the built-in *.template files for
+// all subsequent code generation, matching the --template-overlay flag.
+func SetTemplateOverlayDir(dir string) {
+	activeTemplateOverlayDir = dir
+}
+
+// Emitter is a beamsplitter language backend. createJsCodeGenerator/EmitJavaScript/EditTypeScript,
+// createJavaCodeGenerator/EditJava, and createPyCodeGenerator/EmitPython are each wrapped by one so
+// that main can drive every language backend the same way, and so RegisterEmitter lets a downstream
+// project add its own (Rust, C#, Swift, ...) without forking this file.
+type Emitter interface {
+	// Name is the short identifier used on the command line, e.g. "js", "java", "python".
+	Name() string
+	// TemplateFiles lists the built-in template filenames this emitter parses, in the order it
+	// parses them. --template-overlay shadows these by filename.
+	TemplateFiles() []string
+	// Generate runs the emitter over definitions using cfg.
+	Generate(definitions []TypeDefinition, cfg EmitterConfig) error
+}
+
+var emitterRegistry = map[string]Emitter{}
+
+// RegisterEmitter adds e to the set of emitters main can dispatch to by name. Registering a name
+// that's already taken replaces the previous emitter, so a downstream project can also override a
+// built-in backend instead of only adding new ones.
+func RegisterEmitter(e Emitter) {
+	emitterRegistry[e.Name()] = e
+}
+
+// LookupEmitter returns the registered emitter for name, or nil if none was registered.
+func LookupEmitter(name string) Emitter {
+	return emitterRegistry[name]
+}
+
+// RegisteredEmitters returns every registered emitter, sorted by name for deterministic --help
+// output and iteration order.
+func RegisteredEmitters() []Emitter {
+	names := make([]string, 0, len(emitterRegistry))
+	for name := range emitterRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	emitters := make([]Emitter, len(names))
+	for i, name := range names {
+		emitters[i] = emitterRegistry[name]
+	}
+	return emitters
+}
+
+// resolveTemplatePath returns the overlay copy of templateFile under overlayDir if one exists,
+// otherwise the built-in template file in beamsplitter's own working directory (matching the
+// existing template.ParseFiles("foo.template") behavior).
+func resolveTemplatePath(templateFile string, overlayDir string) string {
+	if overlayDir == "" {
+		return templateFile
+	}
+	overlayPath := filepath.Join(overlayDir, templateFile)
+	if _, err := os.Stat(overlayPath); err == nil {
+		return overlayPath
+	}
+	return templateFile
+}
+
+func init() {
+	RegisterEmitter(&jsEmitter{})
+	RegisterEmitter(&javaEmitter{})
+	RegisterEmitter(&pyEmitter{})
+}
+
+// jsEmitter wraps the existing JavaScript/TypeScript codegen behind the Emitter interface.
+type jsEmitter struct{}
+
+func (*jsEmitter) Name() string { return "js" }
+
+func (*jsEmitter) TemplateFiles() []string { return []string{"javascript.template"} }
+
+func (*jsEmitter) Generate(definitions []TypeDefinition, cfg EmitterConfig) error {
+	SetTemplateOverlayDir(cfg.TemplateOverlayDir)
+	EmitJavaScript(definitions, cfg.Namespace, cfg.OutputFolder)
+	EditTypeScript(definitions, cfg.Namespace, cfg.OutputFolder)
+	return nil
+}
+
+// javaEmitter wraps the existing Java codegen behind the Emitter interface.
+type javaEmitter struct{}
+
+func (*javaEmitter) Name() string { return "java" }
+
+func (*javaEmitter) TemplateFiles() []string { return []string{"java.template"} }
+
+func (*javaEmitter) Generate(definitions []TypeDefinition, cfg EmitterConfig) error {
+	SetTemplateOverlayDir(cfg.TemplateOverlayDir)
+	scopes := make([]Scope, len(definitions))
+	for i, d := range definitions {
+		scope, ok := d.(Scope)
+		if !ok {
+			return fmt.Errorf("java emitter: %T does not implement Scope", d)
+		}
+		scopes[i] = scope
+	}
+	EditJava(scopes, cfg.ClassName, cfg.OutputFolder)
+	return nil
+}
+
+// pyEmitter wraps the existing Python codegen behind the Emitter interface.
+type pyEmitter struct{}
+
+func (*pyEmitter) Name() string { return "python" }
+
+func (*pyEmitter) TemplateFiles() []string { return []string{"python.template"} }
+
+func (*pyEmitter) Generate(definitions []TypeDefinition, cfg EmitterConfig) error {
+	SetTemplateOverlayDir(cfg.TemplateOverlayDir)
+	EmitPython(definitions, cfg.Namespace, cfg.OutputFolder)
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeEmitter struct{ name string }
+
+func (f *fakeEmitter) Name() string { return f.name }
+
+func (f *fakeEmitter) TemplateFiles() []string { return []string{f.name + ".template"} }
+
+func (f *fakeEmitter) Generate([]TypeDefinition, EmitterConfig) error { return nil }
+
+func TestRegisterAndLookupEmitter(t *testing.T) {
+	defer func(saved map[string]Emitter) { emitterRegistry = saved }(emitterRegistry)
+	emitterRegistry = map[string]Emitter{}
+
+	RegisterEmitter(&fakeEmitter{name: "rust"})
+	got := LookupEmitter("rust")
+	if got == nil || got.Name() != "rust" {
+		t.Fatalf("LookupEmitter(%q) = %v, want the registered emitter", "rust", got)
+	}
+	if LookupEmitter("nonexistent") != nil {
+		t.Error("LookupEmitter() of an unregistered name should return nil")
+	}
+}
+
+func TestRegisterEmitterReplacesExisting(t *testing.T) {
+	defer func(saved map[string]Emitter) { emitterRegistry = saved }(emitterRegistry)
+	emitterRegistry = map[string]Emitter{}
+
+	first := &fakeEmitter{name: "rust"}
+	second := &fakeEmitter{name: "rust"}
+	RegisterEmitter(first)
+	RegisterEmitter(second)
+
+	got := LookupEmitter("rust")
+	if got != Emitter(second) {
+		t.Error("RegisterEmitter() did not replace the previously registered emitter for the same name")
+	}
+}
+
+func TestRegisteredEmittersSortedByName(t *testing.T) {
+	defer func(saved map[string]Emitter) { emitterRegistry = saved }(emitterRegistry)
+	emitterRegistry = map[string]Emitter{}
+
+	RegisterEmitter(&fakeEmitter{name: "python"})
+	RegisterEmitter(&fakeEmitter{name: "java"})
+	RegisterEmitter(&fakeEmitter{name: "js"})
+
+	emitters := RegisteredEmitters()
+	var names []string
+	for _, e := range emitters {
+		names = append(names, e.Name())
+	}
+	want := []string{"java", "js", "python"}
+	for i, name := range want {
+		if names[i] != name {
+			t.Errorf("RegisteredEmitters()[%d] = %q, want %q (got %v)", i, names[i], name, names)
+		}
+	}
+}
+
+func TestResolveTemplatePathNoOverlay(t *testing.T) {
+	got := resolveTemplatePath("java.template", "")
+	if got != "java.template" {
+		t.Errorf("resolveTemplatePath() = %q, want the built-in filename unchanged", got)
+	}
+}
+
+func TestResolveTemplatePathOverlayMissingFallsBack(t *testing.T) {
+	dir := t.TempDir()
+	got := resolveTemplatePath("java.template", dir)
+	if got != "java.template" {
+		t.Errorf("resolveTemplatePath() = %q, want fallback to the built-in filename when no overlay exists", got)
+	}
+}
+
+func TestResolveTemplatePathOverlayPresent(t *testing.T) {
+	dir := t.TempDir()
+	overlayPath := filepath.Join(dir, "java.template")
+	if err := os.WriteFile(overlayPath, []byte("{{/* overlay */}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := resolveTemplatePath("java.template", dir)
+	if got != overlayPath {
+		t.Errorf("resolveTemplatePath() = %q, want the overlay path %q", got, overlayPath)
+	}
+}
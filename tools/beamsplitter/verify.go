@@ -0,0 +1,316 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+// This file backs the `--check` flag wired up in main.go: when set, verifyEmitter calls the Verify*
+// function for each Emit*/Edit* it would otherwise run, prints any returned drift, and main exits
+// non-zero instead of touching outputFolder — the same shape as `gofmt -d`/`gofmt -l`.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SortDefinitions orders definitions by fully qualified name so that Emit*/Edit* always visit them
+// in the same order regardless of how the parser discovered them. This, combined with Go's stable
+// map iteration being avoided in the templates, is what makes --check meaningful: without a stable
+// order, regenerating the same inputs could reorder unrelated lines and make every run look dirty.
+func SortDefinitions(definitions []TypeDefinition) {
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return definitionName(definitions[i]) < definitionName(definitions[j])
+	})
+}
+
+// definitionName returns the fully qualified C++ name used to key sorting, caching, and diagnostic
+// output across beamsplitter.
+func definitionName(d TypeDefinition) string {
+	switch t := d.(type) {
+	case *StructDefinition:
+		return t.Name
+	case *EnumDefinition:
+		return t.Name
+	}
+	return ""
+}
+
+// driftError reports that a generated file no longer matches what's committed, along with a
+// unified-style diff so the failure is actionable in CI output (mirroring `gofmt -d`).
+type driftError struct {
+	path string
+	diff string
+}
+
+func (e *driftError) Error() string {
+	return fmt.Sprintf("%s is out of date, run beamsplitter without --check to regenerate:\n%s", e.path, e.diff)
+}
+
+// verifyContent compares want against the current contents of path and returns a *driftError if
+// they differ. A missing file is treated as maximally out of date rather than an I/O error, since
+// that's the common case of a generated file never having been committed.
+func verifyContent(path string, want string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		existing = nil
+	}
+	got := string(existing)
+	if got == want {
+		return nil
+	}
+	return &driftError{path: path, diff: unifiedDiff(path, got, want)}
+}
+
+// unifiedDiff produces a minimal line-oriented diff between got and want, good enough to show a
+// reviewer or CI log what drifted without pulling in an external diff library.
+func unifiedDiff(path, got, want string) string {
+	gotLines := splitLines(got)
+	wantLines := splitLines(want)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	max := len(gotLines)
+	if len(wantLines) > max {
+		max = len(wantLines)
+	}
+	for i := 0; i < max; i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g == w {
+			continue
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&b, "-%s\n", g)
+		}
+		if i < len(wantLines) {
+			fmt.Fprintf(&b, "+%s\n", w)
+		}
+	}
+	return b.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// VerifyJavaScript regenerates the JS bindings into memory and reports any file whose on-disk
+// content has drifted, instead of overwriting outputFolder like EmitJavaScript does. Like
+// EmitJavaScript, it reuses each unchanged definition's previously emitted region (see regions.go)
+// rather than re-rendering everything, so --check only flags real drift instead of the
+// region-marker churn a naive re-render would introduce.
+func VerifyJavaScript(definitions []TypeDefinition, namespace string, outputFolder string) []error {
+	SortDefinitions(definitions)
+	generate := createJsCodeGenerator(namespace, definitions)
+
+	graph, err := BuildDependencyGraph(definitions)
+	if err != nil {
+		return []error{err}
+	}
+	cache := loadCache(outputFolder)
+	changed := changedDefinitions(definitions, graph, cache)
+
+	isStruct := func(d TypeDefinition) bool { _, ok := d.(*StructDefinition); return ok }
+	isEnum := func(d TypeDefinition) bool { _, ok := d.(*EnumDefinition); return ok }
+
+	files := []struct {
+		name                 string
+		header, footer       string
+		perDefinitionSection string
+		wantKind             func(TypeDefinition) bool
+	}{
+		{"jsbindings_generated.cpp", "JsBindingsHeader", "JsBindingsFooter", "JsBindingsStruct", isStruct},
+		{"jsenums_generated.cpp", "JsEnumsHeader", "JsEnumsFooter", "JsEnum", isEnum},
+		{"extensions_generated.js", "JsExtensionsHeader", "JsExtensionsFooter", "JsExtension", isStruct},
+	}
+
+	var errs []error
+	for _, f := range files {
+		path := outputFolder + string(os.PathSeparator) + f.name
+		old, _ := os.ReadFile(path)
+		oldRegions := extractRegions(string(old))
+
+		var body []TypeDefinition
+		for _, d := range definitions {
+			if f.wantKind(d) {
+				body = append(body, d)
+			}
+		}
+		spliced := renderRegions(body, changed, oldRegions, func(d TypeDefinition) string {
+			return renderDefinitionToString(generate, f.perDefinitionSection, d)
+		})
+
+		want := renderDefinitionToString(generate, f.header, nil) + spliced + renderDefinitionToString(generate, f.footer, nil)
+		if err := verifyContent(path, want); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// VerifyJava mirrors EditJava: it re-renders the generated tail of classname+".java", reusing each
+// unchanged definition's previously emitted region the same way EditJava does, and reports drift
+// instead of rewriting the file.
+func VerifyJava(definitions []TypeDefinition, classname string, folder string) error {
+	SortDefinitions(definitions)
+	path := folder + string(os.PathSeparator) + classname + ".java"
+	prefix, err := readUntilMarker(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteString("    // " + CodelineMarker + "\n")
+
+	graph, err := BuildDependencyGraph(definitions)
+	if err != nil {
+		return err
+	}
+	cache := loadCache(folder)
+	changed := changedDefinitions(definitions, graph, cache)
+
+	oldTail, err := readAfterMarker(path)
+	if err != nil {
+		return err
+	}
+	oldRegions := extractRegions(oldTail)
+
+	generate := createJavaCodeGenerator(definitions)
+	spliced := renderRegions(definitions, changed, oldRegions, func(d TypeDefinition) string {
+		switch d.(type) {
+		case *StructDefinition:
+			return renderScopeToString(generate, "Struct", d.(Scope))
+		case *EnumDefinition:
+			return renderScopeToString(generate, "Enum", d.(Scope))
+		}
+		return ""
+	})
+	buf.WriteString(spliced)
+	buf.WriteString("}\n")
+
+	return verifyContent(path, buf.String())
+}
+
+// VerifyTypeScript mirrors EditTypeScript: it re-renders the generated tail of filament.d.ts,
+// reusing each unchanged definition's previously emitted region the same way EditTypeScript does,
+// and reports drift instead of rewriting the file.
+func VerifyTypeScript(definitions []TypeDefinition, namespace string, folder string) error {
+	SortDefinitions(definitions)
+	path := folder + string(os.PathSeparator) + "filament.d.ts"
+	prefix, err := readUntilMarker(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteString("// " + CodelineMarker + "\n")
+
+	graph, err := BuildDependencyGraph(definitions)
+	if err != nil {
+		return err
+	}
+	cache := loadCache(folder)
+	changed := changedDefinitions(definitions, graph, cache)
+
+	oldTail, err := readAfterMarker(path)
+	if err != nil {
+		return err
+	}
+	oldRegions := extractRegions(oldTail)
+
+	generate := createJsCodeGenerator(namespace, definitions)
+	spliced := renderRegions(definitions, changed, oldRegions, func(d TypeDefinition) string {
+		switch d.(type) {
+		case *StructDefinition:
+			return renderDefinitionToString(generate, "TsStruct", d)
+		case *EnumDefinition:
+			return renderDefinitionToString(generate, "TsEnum", d)
+		}
+		return ""
+	})
+	buf.WriteString(spliced)
+
+	return verifyContent(path, buf.String())
+}
+
+// readUntilMarker reads path up to (but not including) the CodelineMarker line, returning the
+// accumulated prefix with trailing newlines preserved, the same way EditJava/EditTypeScript do.
+func readUntilMarker(path string) (string, error) {
+	sourceFile, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer sourceFile.Close()
+
+	var b strings.Builder
+	lineScanner := bufio.NewScanner(sourceFile)
+	foundMarker := false
+	for lineScanner.Scan() {
+		codeline := lineScanner.Text()
+		if strings.Contains(codeline, CodelineMarker) {
+			foundMarker = true
+			break
+		}
+		b.WriteString(codeline)
+		b.WriteString("\n")
+	}
+	if !foundMarker {
+		return "", fmt.Errorf("unable to find marker line in %s", path)
+	}
+	return b.String(), nil
+}
+
+// readAfterMarker reads path from just after the CodelineMarker line to the end, mirroring the
+// oldTail EditJava/EditTypeScript collect while scanning for that same marker, so extractRegions
+// can find the previously emitted regions to splice here too.
+func readAfterMarker(path string) (string, error) {
+	sourceFile, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer sourceFile.Close()
+
+	var tail []string
+	lineScanner := bufio.NewScanner(sourceFile)
+	foundMarker := false
+	for lineScanner.Scan() {
+		codeline := lineScanner.Text()
+		if strings.Contains(codeline, CodelineMarker) {
+			foundMarker = true
+			continue
+		}
+		if foundMarker {
+			tail = append(tail, codeline)
+		}
+	}
+	if !foundMarker {
+		return "", fmt.Errorf("unable to find marker line in %s", path)
+	}
+	return strings.Join(tail, "\n"), nil
+}
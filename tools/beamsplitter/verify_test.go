@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2022 The Android Open Source Project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single line no trailing newline", "a", []string{"a"}},
+		{"trailing newline stripped", "a\nb\n", []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		got := splitLines(test.s)
+		if len(got) != len(test.want) {
+			t.Fatalf("splitLines(%q) = %v, want %v", test.s, got, test.want)
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("splitLines(%q)[%d] = %q, want %q", test.s, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestUnifiedDiffIdenticalContent(t *testing.T) {
+	diff := unifiedDiff("foo.java", "same\n", "same\n")
+	if strings.Contains(diff, "-same") || strings.Contains(diff, "+same") {
+		t.Errorf("unifiedDiff() reported a difference for identical content: %q", diff)
+	}
+}
+
+func TestUnifiedDiffReportsChangedLine(t *testing.T) {
+	diff := unifiedDiff("foo.java", "one\ntwo\n", "one\nthree\n")
+	if !strings.Contains(diff, "-two") || !strings.Contains(diff, "+three") {
+		t.Errorf("unifiedDiff() = %q, want it to show -two and +three", diff)
+	}
+}
+
+func TestVerifyContentMatchesOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Generated.java")
+	if err := os.WriteFile(path, []byte("same content\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyContent(path, "same content\n"); err != nil {
+		t.Errorf("verifyContent() = %v, want nil for matching content", err)
+	}
+}
+
+func TestVerifyContentDetectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Generated.java")
+	if err := os.WriteFile(path, []byte("old content\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := verifyContent(path, "new content\n")
+	if err == nil {
+		t.Fatal("verifyContent() = nil, want a driftError for mismatched content")
+	}
+	if _, ok := err.(*driftError); !ok {
+		t.Errorf("verifyContent() error type = %T, want *driftError", err)
+	}
+}
+
+func TestVerifyContentMissingFileIsDrift(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "DoesNotExist.java")
+	if err := verifyContent(path, "new content\n"); err == nil {
+		t.Error("verifyContent() = nil, want a driftError for a missing file")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+// Minimal stand-ins for types normally defined in parser.go, which isn't part of this
+// repo slice, so the package can be compiled and tested in isolation for review purposes.
+
+const CodelineMarker = "CODE-GENERATOR-MARKER"
+
+type Scope interface {
+	isScope()
+}
+
+type TypeDefinition interface {
+	isTypeDefinition()
+}
+
+type Field struct {
+	Name    string
+	CppType string
+}
+
+type StructDefinition struct {
+	Name   string
+	Fields []Field
+}
+
+func (*StructDefinition) isScope()          {}
+func (*StructDefinition) isTypeDefinition() {}
+
+type EnumDefinition struct {
+	Name   string
+	Values []string
+}
+
+func (*EnumDefinition) isScope()          {}
+func (*EnumDefinition) isTypeDefinition() {}
+
+func Parse(args []string) []TypeDefinition { return nil }